@@ -0,0 +1,19 @@
+//go:build linux
+
+package main
+
+import (
+	"go.xrstf.de/ubahnmapper/pkg/i2c"
+	"go.xrstf.de/ubahnmapper/pkg/i2c/linux"
+)
+
+// openLinuxBus opens device (e.g. "/dev/i2c-1") directly via the Linux
+// character device, for running without an MCP2221A USB bridge.
+func openLinuxBus(device string) (i2c.Bus, func() error, error) {
+	bus, err := linux.Open(device)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bus, bus.Close, nil
+}
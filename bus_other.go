@@ -0,0 +1,15 @@
+//go:build !linux
+
+package main
+
+import (
+	"fmt"
+
+	"go.xrstf.de/ubahnmapper/pkg/i2c"
+)
+
+// openLinuxBus is a stub for platforms other than Linux, where there is no
+// /dev/i2c-N character device to open directly.
+func openLinuxBus(device string) (i2c.Bus, func() error, error) {
+	return nil, nil, fmt.Errorf("direct Linux I²C bus access (%s) requires building for linux", device)
+}
@@ -12,12 +12,15 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+
+	"go.xrstf.de/ubahnmapper/pkg/detect"
 )
 
 var (
 	timeShift     = pflag.DurationP("time-shift", "s", 0, "shift start of timeseries by this much time (Go duration, e.g. '30m')")
-	collapseStops = pflag.DurationP("collapse-stops", "c", 0, "collapse all data points between ' an' and ' ab' events (Go duration, e.g. '30m') (requires --protocol)")
+	collapseStops = pflag.DurationP("collapse-stops", "c", 0, "collapse all data points between ' an' and ' ab' events (Go duration, e.g. '30m') (requires --protocol or --auto-stops)")
 	protocolFile  = pflag.StringP("protocol", "p", "", "protocol CSV file")
+	autoStops     = pflag.DurationP("auto-stops", "a", 0, "auto-detect stops instead of reading --protocol, by tracking a streaming quantile of |dP/dt|; the value sets the minimum stop duration")
 	runID         = pflag.StringP("run-id", "i", "", "unique identifier for this timeseries")
 	timezone      = pflag.StringP("timezone", "t", "Europe/Berlin", "timezone to interpret the timestamps with")
 	basePressure  = pflag.Float64P("base-pressure", "b", 0, "instead of taking the first datapoint as the base pressure, use this value")
@@ -70,6 +73,13 @@ func main() {
 		}
 
 		dataTimeseries = combinedTimeseries
+	} else if *autoStops > 0 {
+		autoTimeseries, err := autoDetectStops(dataTimeseries, *autoStops)
+		if err != nil {
+			log.Fatalf("Failed to auto-detect stops: %v", err)
+		}
+
+		dataTimeseries = autoTimeseries
 	}
 
 	if *eventRange {
@@ -234,6 +244,41 @@ func normalizeTimeseries(data *Timeseries, timeShift *time.Duration, basePressur
 	return result, nil
 }
 
+// autoDetectStops runs pkg/detect.StopFinder over the pressure data and
+// synthesizes "stopN an"/"stopN ab" events for every stop found, so
+// collapseStopsInTimeseries can run without a hand-written protocol file.
+func autoDetectStops(data *Timeseries, minStopDuration time.Duration) (*Timeseries, error) {
+	result := &Timeseries{
+		Points: append([]Datapoint{}, data.Points...),
+	}
+
+	finder := detect.NewStopFinder()
+	finder.MinStopDuration = minStopDuration
+
+	for _, p := range result.Points {
+		finder.Push(p.Recorded, p.Pressure)
+	}
+
+	for i, stop := range finder.Stops() {
+		name := fmt.Sprintf("stop%d", i+1)
+
+		markEvent(result.Points, stop.Start, name+" an")
+		markEvent(result.Points, stop.End, name+" ab")
+	}
+
+	return result, nil
+}
+
+// markEvent sets the event on the first point at or after t.
+func markEvent(points []Datapoint, t time.Time, event string) {
+	for i, p := range points {
+		if !p.Recorded.Before(t) {
+			points[i].Event = event
+			return
+		}
+	}
+}
+
 func trimTimeseries(data *Timeseries) (*Timeseries, error) {
 	firstEvent := -1
 	for i, p := range data.Points {
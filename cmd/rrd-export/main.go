@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/ziutek/rrd"
+)
+
+var (
+	from       = pflag.StringP("from", "f", "", "start of the exported range (RFC3339)")
+	to         = pflag.StringP("to", "t", "", "end of the exported range (RFC3339)")
+	cf         = pflag.StringP("cf", "c", "AVERAGE", "consolidation function to fetch (AVERAGE, MAX)")
+	resolution = pflag.DurationP("resolution", "r", 0, "requested resolution; rrdtool picks the best matching archive if this is 0")
+)
+
+func main() {
+	pflag.Parse()
+
+	if pflag.NArg() == 0 {
+		log.Fatal("No RRD file given.")
+	}
+
+	if *from == "" || *to == "" {
+		log.Fatal("--from and --to are required.")
+	}
+
+	fromTime, err := time.Parse(time.RFC3339, *from)
+	if err != nil {
+		log.Fatalf("Invalid --from: %v", err)
+	}
+
+	toTime, err := time.Parse(time.RFC3339, *to)
+	if err != nil {
+		log.Fatalf("Invalid --to: %v", err)
+	}
+
+	result, err := rrd.Fetch(pflag.Arg(0), *cf, fromTime, toTime, *resolution)
+	if err != nil {
+		log.Fatalf("Failed to fetch from RRD database: %v", err)
+	}
+	defer result.FreeValues()
+
+	dsIndex := -1
+
+	for i, name := range result.DsNames {
+		if name == "pressure" {
+			dsIndex = i
+			break
+		}
+	}
+
+	if dsIndex == -1 {
+		log.Fatal("RRD database has no \"pressure\" data source.")
+	}
+
+	// header row, matching what cmd/importer's loadData expects
+	fmt.Println("time;pressure")
+
+	t := result.Start.Add(result.Step)
+	for row := 0; row < result.RowCnt; row++ {
+		v := result.ValueAt(dsIndex, row)
+		if !math.IsNaN(v) {
+			fmt.Printf("%s;%v\n", t.Format("2006-01-02T15:04:05.999999999"), v)
+		}
+
+		t = t.Add(result.Step)
+	}
+}
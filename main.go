@@ -1,32 +1,68 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	mcp "github.com/ardnew/mcp2221a"
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/ubahnmapper/pkg/capture"
+	"go.xrstf.de/ubahnmapper/pkg/i2c"
+	"go.xrstf.de/ubahnmapper/pkg/i2c/mcp2221"
 	"go.xrstf.de/ubahnmapper/pkg/lps25"
+	"go.xrstf.de/ubahnmapper/pkg/rrd"
+	"go.xrstf.de/ubahnmapper/pkg/sink"
+)
+
+var (
+	runID         = pflag.StringP("run-id", "i", "", "unique identifier for this recording, stamped onto every point as the run_id tag")
+	tagList       = pflag.StringP("tags", "T", "", "additional tags to attach to every point, as a comma-separated k=v list")
+	influxURL     = pflag.String("influx-url", "", "InfluxDB v2 server URL (e.g. http://localhost:8086); if unset, points are only written to --csv-file")
+	influxToken   = pflag.String("influx-token", "", "InfluxDB v2 API token")
+	influxOrg     = pflag.String("influx-org", "", "InfluxDB v2 organization")
+	influxBucket  = pflag.String("influx-bucket", "", "InfluxDB v2 bucket")
+	csvFile       = pflag.String("csv-file", "ubahnmapper.csv", "line-protocol file to buffer points in, and to fall back to if InfluxDB becomes unreachable")
+	flushInterval = pflag.Duration("flush-interval", 1*time.Second, "passed to the InfluxDB sink, which writes each point immediately and does not use it; a degraded InfluxDB connection is retried every 5s regardless of this value")
+	rrdFile       = pflag.String("rrd-file", "", "RRDtool database to additionally stream batched pressure samples into (created if missing)")
+	rrdRetention  = pflag.Duration("rrd-retention", 48*time.Hour, "how much history to retain in --rrd-file at each archive resolution")
+	rrdFlushEvery = pflag.Int("rrd-flush-every", 25, "how many samples to batch before writing them to --rrd-file")
+	listenAddr    = pflag.StringP("listen-addr", "l", ":8080", "address for the live-view HTTP server (SSE at /stream, WebSocket at /ws, markers via POST /event)")
+	ringWindow    = pflag.Duration("ring-window", 10*time.Minute, "how much history late-joining live-view clients are sent on connect")
+	bus           = pflag.String("bus", "mcp2221", `I²C bus to use: "mcp2221" for the default MCP2221A USB bridge, or a Linux /dev/i2c-N device path to talk to the bus directly (e.g. running on an SBC inside the train)`)
 )
 
 func main() {
-	m, err := mcp.New(0, mcp.VID, mcp.PID)
+	pflag.Parse()
+
+	if *runID == "" {
+		log.Fatal("No --run-id given.")
+	}
+
+	tags, err := parseTags(*tagList)
 	if err != nil {
-		log.Fatalf("Failed to open mcp2221a device: %v", err)
+		log.Fatalf("Invalid --tags: %v", err)
 	}
-	defer m.Close()
+	tags["run_id"] = *runID
 
-	// reset device to default settings stored in flash memory
-	// if err := m.Reset(5 * time.Second); err != nil {
-	// 	log.Fatalf("Failed to reset device: %v", err)
-	// }
+	dataSink, err := newSink()
+	if err != nil {
+		log.Fatalf("Failed to set up sink: %v", err)
+	}
+	defer dataSink.Close()
 
-	// configure I2C module to use default baud rate (optional)
-	if err := m.I2C.SetConfig(mcp.I2CBaudRate); err != nil {
-		log.Fatalf("Failed to setup I²C bus: %v", err)
+	i2cBus, closeBus, err := openBus(*bus)
+	if err != nil {
+		log.Fatalf("Failed to open I²C bus %q: %v", *bus, err)
 	}
+	defer closeBus()
 
-	sensor := lps25.NewSensor(m.I2C, 0) // 0 = default address
+	sensor := lps25.NewSensor(i2cBus, 0) // 0 = default address
 
 	enabled, err := sensor.Enabled()
 	if err != nil {
@@ -45,13 +81,87 @@ func main() {
 		log.Fatalf("Failed to set sensor data rate: %v", err)
 	}
 
-	for {
-		pressure, err := sensor.Pressure()
+	recorder := capture.NewRecorder(sensor, dataSink, tags, 40*time.Millisecond, *ringWindow)
+	server := capture.NewServer(recorder)
+
+	go func() {
+		log.Printf("live view listening on %s", *listenAddr)
+
+		if err := http.ListenAndServe(*listenAddr, server.Handler()); err != nil {
+			log.Fatalf("Live-view HTTP server failed: %v", err)
+		}
+	}()
+
+	if err := recorder.Run(context.Background()); err != nil {
+		log.Fatalf("Failed to record: %v", err)
+	}
+}
+
+// openBus opens the I²C bus named by spec: "mcp2221" for the default
+// MCP2221A USB bridge, or a Linux /dev/i2c-N device path to talk to the
+// bus directly (e.g. running on an SBC inside the train, without a USB
+// bridge in between). It returns a close func for whatever it opened.
+func openBus(spec string) (i2c.Bus, func() error, error) {
+	if spec == "mcp2221" {
+		m, err := mcp.New(0, mcp.VID, mcp.PID)
 		if err != nil {
-			log.Fatalf("Failed to read pressure: %v", err)
+			return nil, nil, fmt.Errorf("failed to open mcp2221a device: %w", err)
+		}
+
+		// configure I2C module to use default baud rate (optional)
+		if err := m.I2C.SetConfig(mcp.I2CBaudRate); err != nil {
+			m.Close()
+			return nil, nil, fmt.Errorf("failed to setup I²C bus: %w", err)
+		}
+
+		return mcp2221.New(m.I2C), m.Close, nil
+	}
+
+	return openLinuxBus(spec)
+}
+
+// newSink builds the sink the recorder writes to.
+func newSink() (sink.Sink, error) {
+	fileSink, err := sink.NewFile(*csvFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", *csvFile, err)
+	}
+
+	var dataSink sink.Sink = fileSink
+
+	if *influxURL != "" {
+		influxSink := sink.NewInflux(*influxURL, *influxToken, *influxOrg, *influxBucket, *flushInterval)
+		dataSink = sink.WithFallback(influxSink, fileSink)
+	}
+
+	if *rrdFile != "" {
+		if _, err := os.Stat(*rrdFile); os.IsNotExist(err) {
+			if err := rrd.Create(*rrdFile, time.Now(), *rrdRetention); err != nil {
+				return nil, fmt.Errorf("failed to create %s: %w", *rrdFile, err)
+			}
 		}
 
-		fmt.Printf("pressure: %f hPa\n", pressure)
-		time.Sleep(1 * time.Second)
+		dataSink = sink.NewMulti(dataSink, rrd.NewSink(*rrdFile, *rrdFlushEvery))
 	}
+
+	return dataSink, nil
+}
+
+func parseTags(raw string) (map[string]string, error) {
+	tags := map[string]string{}
+
+	if raw == "" {
+		return tags, nil
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tag %q, expected k=v", pair)
+		}
+
+		tags[key] = value
+	}
+
+	return tags, nil
 }
@@ -0,0 +1,183 @@
+// Package capture drives the sensor reading loop and makes it watchable
+// live: every sample is persisted through a sink.Sink and also fanned out
+// to HTTP clients via the Server in server.go, so a phone browser can watch
+// the pressure curve while the train is moving.
+package capture
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go.xrstf.de/ubahnmapper/pkg/sink"
+)
+
+// Sample is a single pressure reading, optionally carrying an event marker
+// (either from a protocol or injected live via Server's /event endpoint).
+type Sample struct {
+	Time     time.Time
+	Pressure float32
+	Event    string
+}
+
+// PressureReader is the part of lps25.Sensor the Recorder needs.
+type PressureReader interface {
+	Pressure() (float32, error)
+}
+
+// Recorder runs PressureReader on an internal ticker, persists every
+// sample through a sink.Sink, and fans it out to live subscribers.
+type Recorder struct {
+	reader PressureReader
+	sink   sink.Sink
+
+	interval          time.Duration
+	reconnectInterval time.Duration
+	reconnect         sink.Reconnecting
+	tags              map[string]string
+
+	ring *ring
+
+	mu          sync.Mutex
+	subscribers map[chan Sample]struct{}
+
+	pendingEvent chan string
+}
+
+// NewRecorder prepares a Recorder that reads from reader every interval,
+// persists samples to dataSink (tagged with tags), and keeps ringWindow
+// worth of history for clients that join a live view mid-ride.
+func NewRecorder(reader PressureReader, dataSink sink.Sink, tags map[string]string, interval, ringWindow time.Duration) *Recorder {
+	reconnect, _ := dataSink.(sink.Reconnecting)
+
+	return &Recorder{
+		reader:            reader,
+		sink:              dataSink,
+		interval:          interval,
+		reconnectInterval: 5 * time.Second,
+		reconnect:         reconnect,
+		tags:              tags,
+		ring:              newRing(ringWindow),
+		subscribers:       map[chan Sample]struct{}{},
+		pendingEvent:      make(chan string, 1),
+	}
+}
+
+// Run reads and persists samples until ctx is cancelled or a read fails.
+func (r *Recorder) Run(ctx context.Context) error {
+	dataTicker := time.NewTicker(r.interval)
+	defer dataTicker.Stop()
+
+	reconnectTicker := time.NewTicker(r.reconnectInterval)
+	defer reconnectTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-reconnectTicker.C:
+			if r.reconnect != nil {
+				if err := r.reconnect.Reconnect(); err != nil {
+					log.Printf("still unable to reach InfluxDB: %v", err)
+				}
+			}
+
+		case <-dataTicker.C:
+			if err := r.tick(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (r *Recorder) tick() error {
+	pressure, err := r.reader.Pressure()
+	if err != nil {
+		return fmt.Errorf("failed to read pressure: %w", err)
+	}
+
+	sample := Sample{
+		Time:     time.Now(),
+		Pressure: pressure,
+		Event:    r.takeEvent(),
+	}
+
+	r.ring.add(sample)
+	r.broadcast(sample)
+
+	if err := r.sink.Write(sink.Point{
+		Time:     sample.Time,
+		Pressure: float64(sample.Pressure),
+		Raw:      float64(sample.Pressure),
+		Event:    sample.Event,
+		Tags:     r.tags,
+	}); err != nil {
+		log.Printf("failed to persist sample: %v", err)
+	}
+
+	return nil
+}
+
+// InjectEvent marks the next recorded sample with name, e.g. "Alexanderplatz an".
+// It's how Server's POST /event lets the operator drop a station marker from
+// their phone instead of a hand-written protocol file; the marker ends up
+// in the same sink (line-protocol file/InfluxDB/RRD) as any other event.
+func (r *Recorder) InjectEvent(name string) {
+	select {
+	case r.pendingEvent <- name:
+	default:
+		// an event is already waiting to be attached to the next sample;
+		// operators mark one station at a time, so this shouldn't happen
+	}
+}
+
+func (r *Recorder) takeEvent() string {
+	select {
+	case name := <-r.pendingEvent:
+		return name
+	default:
+		return ""
+	}
+}
+
+// Recent returns the samples still inside the ring buffer's window, so a
+// newly connected client gets context instead of starting from a blank
+// chart.
+func (r *Recorder) Recent() []Sample {
+	return r.ring.snapshot()
+}
+
+// Subscribe registers a channel that receives every sample recorded from
+// now on. Call the returned cancel func when done to stop the channel from
+// leaking.
+func (r *Recorder) Subscribe() (<-chan Sample, func()) {
+	ch := make(chan Sample, 16)
+
+	r.mu.Lock()
+	r.subscribers[ch] = struct{}{}
+	r.mu.Unlock()
+
+	cancel := func() {
+		r.mu.Lock()
+		delete(r.subscribers, ch)
+		r.mu.Unlock()
+	}
+
+	return ch, cancel
+}
+
+func (r *Recorder) broadcast(s Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for ch := range r.subscribers {
+		select {
+		case ch <- s:
+		default:
+			// a slow client falls behind rather than stalling capture
+		}
+	}
+}
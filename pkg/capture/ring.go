@@ -0,0 +1,42 @@
+package capture
+
+import (
+	"sync"
+	"time"
+)
+
+// ring keeps the samples recorded within the last `window`, so late-joining
+// clients can be given some history instead of an empty chart.
+type ring struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	samples []Sample
+}
+
+func newRing(window time.Duration) *ring {
+	return &ring{window: window}
+}
+
+func (r *ring) add(s Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.samples = append(r.samples, s)
+
+	cutoff := s.Time.Add(-r.window)
+
+	i := 0
+	for i < len(r.samples) && r.samples[i].Time.Before(cutoff) {
+		i++
+	}
+
+	r.samples = r.samples[i:]
+}
+
+func (r *ring) snapshot() []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Sample{}, r.samples...)
+}
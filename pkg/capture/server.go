@@ -0,0 +1,159 @@
+package capture
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+//go:embed assets/live.html
+var assets embed.FS
+
+// Frame is the JSON shape sent to /stream and /ws clients.
+type Frame struct {
+	T     int64   `json:"t"`
+	P     float32 `json:"p"`
+	Event string  `json:"event,omitempty"`
+}
+
+func toFrame(s Sample) Frame {
+	return Frame{T: s.Time.UnixMilli(), P: s.Pressure, Event: s.Event}
+}
+
+var upgrader = websocket.Upgrader{
+	// the phone watching the live view connects from a different origin
+	// than whatever serves this page during development
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Server exposes a Recorder over HTTP: the live-view page, a Server-Sent
+// Events stream, a WebSocket stream, and an endpoint to inject station
+// markers from the phone that's watching.
+type Server struct {
+	recorder *Recorder
+}
+
+// NewServer wraps recorder in an HTTP handler.
+func NewServer(recorder *Recorder) *Server {
+	return &Server{recorder: recorder}
+}
+
+// Handler returns the http.Handler to pass to http.ListenAndServe.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/stream", s.handleStream)
+	mux.HandleFunc("/ws", s.handleWS)
+	mux.HandleFunc("/event", s.handleEvent)
+
+	return mux
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := assets.ReadFile("assets/live.html")
+	if err != nil {
+		http.Error(w, "asset missing", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(data)
+}
+
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	for _, sample := range s.recorder.Recent() {
+		writeSSE(w, sample)
+	}
+	flusher.Flush()
+
+	samples, cancel := s.recorder.Subscribe()
+	defer cancel()
+
+	ctx := r.Context()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case sample := <-samples:
+			writeSSE(w, sample)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSE(w io.Writer, s Sample) {
+	data, err := json.Marshal(toFrame(s))
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(w, "data: %s\n\n", data)
+}
+
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("failed to upgrade websocket connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	for _, sample := range s.recorder.Recent() {
+		if err := conn.WriteJSON(toFrame(sample)); err != nil {
+			return
+		}
+	}
+
+	samples, cancel := s.recorder.Subscribe()
+	defer cancel()
+
+	for sample := range samples {
+		if err := conn.WriteJSON(toFrame(sample)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handleEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "missing name", http.StatusBadRequest)
+		return
+	}
+
+	s.recorder.InjectEvent(name)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,90 @@
+// Package detect finds stops in a pressure timeseries without needing a
+// hand-written protocol file, by tracking a streaming approximation of the
+// pressure derivative's quantiles.
+package detect
+
+import (
+	"math"
+	"sort"
+)
+
+// entry is a single (value, g, delta) tuple of the summary, keeping the
+// Greenwald-Khanna invariant g+delta <= floor(2*epsilon*n).
+type entry struct {
+	value float64
+	g     int
+	delta int
+}
+
+// Quantile is a streaming, epsilon-approximate quantile estimator
+// implementing the Greenwald-Khanna algorithm ("Space-Efficient Online
+// Computation of Quantile Summaries") - the same kind of biased summary
+// the Prometheus ecosystem uses via beorn7/perks.
+type Quantile struct {
+	epsilon float64
+	n       int
+	entries []entry
+}
+
+// NewQuantile creates a summary that approximates any quantile to within
+// epsilon of the true rank.
+func NewQuantile(epsilon float64) *Quantile {
+	return &Quantile{epsilon: epsilon}
+}
+
+// Insert adds a new observation to the summary.
+func (q *Quantile) Insert(value float64) {
+	q.n++
+
+	i := sort.Search(len(q.entries), func(i int) bool {
+		return q.entries[i].value >= value
+	})
+
+	delta := int(2 * q.epsilon * float64(q.n))
+	if i == 0 || i == len(q.entries) {
+		delta = 0
+	}
+
+	q.entries = append(q.entries, entry{})
+	copy(q.entries[i+1:], q.entries[i:])
+	q.entries[i] = entry{value: value, g: 1, delta: delta}
+
+	compressEvery := int(1 / (2 * q.epsilon))
+	if compressEvery > 0 && q.n%compressEvery == 0 {
+		q.compress()
+	}
+}
+
+// compress merges adjacent tuples whose combined g+delta still satisfies
+// the invariant, keeping the summary's size roughly O(1/epsilon * log(epsilon*n)).
+func (q *Quantile) compress() {
+	threshold := int(2 * q.epsilon * float64(q.n))
+
+	for i := len(q.entries) - 2; i >= 1; i-- {
+		if q.entries[i].g+q.entries[i+1].g+q.entries[i+1].delta <= threshold {
+			q.entries[i+1].g += q.entries[i].g
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+		}
+	}
+}
+
+// Query returns an approximation of the r-quantile (r in (0, 1]) by walking
+// the summary and accumulating g until the desired rank ceil(r*n) is
+// reached.
+func (q *Quantile) Query(r float64) float64 {
+	if len(q.entries) == 0 {
+		return 0
+	}
+
+	target := int(math.Ceil(r * float64(q.n)))
+
+	g := 0
+	for _, e := range q.entries {
+		g += e.g
+		if g >= target {
+			return e.value
+		}
+	}
+
+	return q.entries[len(q.entries)-1].value
+}
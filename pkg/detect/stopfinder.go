@@ -0,0 +1,134 @@
+package detect
+
+import (
+	"math"
+	"time"
+)
+
+// Interval is a detected stop, from arrival to departure.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+type sample struct {
+	t     time.Time
+	value float64
+}
+
+// StopFinder classifies stretches of a pressure timeseries as "stopped"
+// without a hand-written protocol file. It keeps the run-wide 0.5-quantile
+// of |dP/dt| as an adaptive baseline, and calls a stop whenever the rolling
+// 0.9-quantile over the last Window stays below ThresholdRatio of that
+// baseline for at least MinStopDuration.
+//
+// The classic Greenwald-Khanna summary has no way to forget old
+// observations, so the rolling quantile can't be a single long-lived
+// sketch; instead StopFinder keeps the raw derivative samples that fall
+// inside Window and rebuilds a fresh summary from them on every Push. That
+// trades away some of GK's memory-efficiency, but the timeseries here is
+// small and slow enough (one sample per ~40ms) for it not to matter.
+type StopFinder struct {
+	Window          time.Duration
+	MinStopDuration time.Duration
+	ThresholdRatio  float64
+	Epsilon         float64
+
+	runQuantile *Quantile
+	window      []sample
+
+	last sample
+	have bool
+
+	inStop    bool
+	stopStart time.Time
+
+	stops []Interval
+}
+
+// NewStopFinder returns a StopFinder with sensible defaults: a 30s rolling
+// window, a 10s minimum stop duration, and a stop threshold at 5% of the
+// run-wide median derivative.
+func NewStopFinder() *StopFinder {
+	return &StopFinder{
+		Window:          30 * time.Second,
+		MinStopDuration: 10 * time.Second,
+		ThresholdRatio:  0.05,
+		Epsilon:         0.01,
+		runQuantile:     NewQuantile(0.01),
+	}
+}
+
+// Push feeds a new pressure sample at time t into the detector.
+func (f *StopFinder) Push(t time.Time, pressure float64) {
+	defer func() {
+		f.last = sample{t: t, value: pressure}
+		f.have = true
+	}()
+
+	if !f.have {
+		return
+	}
+
+	dt := t.Sub(f.last.t).Seconds()
+	if dt <= 0 {
+		return
+	}
+
+	deriv := math.Abs((pressure - f.last.value) / dt)
+
+	f.runQuantile.Insert(deriv)
+	f.window = append(f.window, sample{t: t, value: deriv})
+	f.trimWindow(t)
+	f.evaluate(t)
+}
+
+func (f *StopFinder) trimWindow(now time.Time) {
+	cutoff := now.Add(-f.Window)
+
+	i := 0
+	for i < len(f.window) && f.window[i].t.Before(cutoff) {
+		i++
+	}
+
+	f.window = f.window[i:]
+}
+
+func (f *StopFinder) evaluate(now time.Time) {
+	if len(f.window) == 0 {
+		return
+	}
+
+	windowQuantile := NewQuantile(f.Epsilon)
+	for _, s := range f.window {
+		windowQuantile.Insert(s.value)
+	}
+
+	p90 := windowQuantile.Query(0.9)
+	threshold := f.ThresholdRatio * f.runQuantile.Query(0.5)
+	stopped := p90 <= threshold
+
+	switch {
+	case stopped && !f.inStop:
+		f.inStop = true
+		f.stopStart = f.window[0].t
+	case !stopped && f.inStop:
+		f.inStop = false
+
+		if now.Sub(f.stopStart) >= f.MinStopDuration {
+			f.stops = append(f.stops, Interval{Start: f.stopStart, End: now})
+		}
+	}
+}
+
+// Stops returns every stop detected so far. A stop still in progress when
+// Stops is called is included, ending at the last sample seen by Push.
+func (f *StopFinder) Stops() []Interval {
+	stops := f.stops
+
+	if f.inStop && f.last.t.Sub(f.stopStart) >= f.MinStopDuration {
+		stops = append(stops, Interval{Start: f.stopStart, End: f.last.t})
+	}
+
+	return stops
+}
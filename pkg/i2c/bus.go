@@ -0,0 +1,21 @@
+// Package i2c defines a bus abstraction that sensor drivers in this module
+// program against, so a driver written once can run on top of different
+// I²C bridges (an MCP2221A USB-to-I²C adapter, a Linux /dev/i2c-N bus, ...)
+// without depending on any of them directly.
+package i2c
+
+// Bus is a minimal I²C master interface. Sensor drivers should take a Bus
+// instead of a concrete bridge type and let the caller decide which
+// implementation to wire up.
+type Bus interface {
+	// ReadReg reads n bytes starting at register reg on the device at addr.
+	ReadReg(addr, reg byte, n int) ([]byte, error)
+	// WriteReg writes data to register reg on the device at addr.
+	WriteReg(addr, reg byte, data []byte) error
+	// Read reads n bytes from the device at addr without addressing a
+	// register first.
+	Read(addr byte, n int) ([]byte, error)
+	// Write writes data to the device at addr without addressing a
+	// register first.
+	Write(addr byte, data []byte) error
+}
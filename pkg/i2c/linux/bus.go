@@ -0,0 +1,101 @@
+//go:build linux
+
+// Package linux adapts a Linux /dev/i2c-N character device to the generic
+// pkg/i2c.Bus interface, so the drivers in this module can run directly on
+// an SBC's I²C bus without an MCP2221A USB bridge in between.
+package linux
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/sys/unix"
+
+	"go.xrstf.de/ubahnmapper/pkg/i2c"
+)
+
+const ioctlI2CSlave = 0x0703
+
+// Bus talks to devices on a Linux I²C bus through a /dev/i2c-N character
+// device, rebinding the slave address with ioctl(I2C_SLAVE) as needed.
+type Bus struct {
+	f *os.File
+
+	mu    sync.Mutex
+	addr  byte
+	bound bool
+}
+
+var _ i2c.Bus = &Bus{}
+
+// Open opens a Linux I²C bus device, e.g. "/dev/i2c-1".
+func Open(device string) (*Bus, error) {
+	f, err := os.OpenFile(device, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", device, err)
+	}
+
+	return &Bus{f: f}, nil
+}
+
+// Close releases the underlying device file.
+func (b *Bus) Close() error {
+	return b.f.Close()
+}
+
+func (b *Bus) selectSlave(addr byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.bound && b.addr == addr {
+		return nil
+	}
+
+	if err := unix.IoctlSetInt(int(b.f.Fd()), ioctlI2CSlave, int(addr)); err != nil {
+		return fmt.Errorf("failed to select slave address 0x%02x: %w", addr, err)
+	}
+
+	b.addr = addr
+	b.bound = true
+
+	return nil
+}
+
+func (b *Bus) ReadReg(addr, reg byte, n int) ([]byte, error) {
+	if err := b.Write(addr, []byte{reg}); err != nil {
+		return nil, err
+	}
+
+	return b.Read(addr, n)
+}
+
+func (b *Bus) WriteReg(addr, reg byte, data []byte) error {
+	return b.Write(addr, append([]byte{reg}, data...))
+}
+
+func (b *Bus) Read(addr byte, n int) ([]byte, error) {
+	if err := b.selectSlave(addr); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(b.f, buf); err != nil {
+		return nil, fmt.Errorf("failed to read %d bytes from 0x%02x: %w", n, addr, err)
+	}
+
+	return buf, nil
+}
+
+func (b *Bus) Write(addr byte, data []byte) error {
+	if err := b.selectSlave(addr); err != nil {
+		return err
+	}
+
+	if _, err := b.f.Write(data); err != nil {
+		return fmt.Errorf("failed to write %d bytes to 0x%02x: %w", len(data), addr, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+// Package mcp2221 adapts an MCP2221A USB-to-I²C bridge to the generic
+// pkg/i2c.Bus interface.
+package mcp2221
+
+import (
+	"github.com/ardnew/mcp2221a"
+
+	"go.xrstf.de/ubahnmapper/pkg/i2c"
+)
+
+// Bus wraps an *mcp2221a.I2C so it satisfies i2c.Bus.
+type Bus struct {
+	i2c *mcp2221a.I2C
+}
+
+var _ i2c.Bus = &Bus{}
+
+// New wraps an already configured MCP2221A I²C module.
+func New(bus *mcp2221a.I2C) *Bus {
+	return &Bus{i2c: bus}
+}
+
+func (b *Bus) ReadReg(addr, reg byte, n int) ([]byte, error) {
+	return b.i2c.ReadReg(addr, reg, uint16(n))
+}
+
+func (b *Bus) WriteReg(addr, reg byte, data []byte) error {
+	payload := append([]byte{reg}, data...)
+
+	return b.i2c.Write(true, addr, payload, uint16(len(payload)))
+}
+
+func (b *Bus) Read(addr byte, n int) ([]byte, error) {
+	return b.i2c.Read(true, addr, uint16(n))
+}
+
+func (b *Bus) Write(addr byte, data []byte) error {
+	return b.i2c.Write(true, addr, data, uint16(len(data)))
+}
@@ -3,7 +3,7 @@ package lps25
 import (
 	"fmt"
 
-	"github.com/ardnew/mcp2221a"
+	"go.xrstf.de/ubahnmapper/pkg/i2c"
 )
 
 func hasBit(b byte, pos uint8) bool {
@@ -37,22 +37,33 @@ const (
 
 	ControlRegister2 = 0x21
 	SwResetBit       = 2
+	FIFOEnableBit    = 6
 
 	PressureOutXLRegister = 0x28
+	TempOutLRegister      = 0x2B
+
+	FIFOCtrlRegister   = 0x2E
+	FIFOStatusRegister = 0x2F
+	FIFOModeMask       = 0xE0
+	FIFOModeShift      = 5
+	FIFOWatermarkMask  = 0x1F
+	FIFOSamplesMask    = 0x1F
 )
 
 type Sensor struct {
-	i2c     *mcp2221a.I2C
+	i2c     i2c.Bus
 	address uint8
+
+	fifoMode FIFOMode
 }
 
-func NewSensor(i2c *mcp2221a.I2C, address uint8) *Sensor {
+func NewSensor(bus i2c.Bus, address uint8) *Sensor {
 	if address == 0 {
 		address = DefaultI2CAddress
 	}
 
 	return &Sensor{
-		i2c:     i2c,
+		i2c:     bus,
 		address: address,
 	}
 }
@@ -81,7 +92,7 @@ func (s Sensor) patchRegister(register uint8, patch func(byte) byte) error {
 		return fmt.Errorf("failed to read current register: %w", err)
 	}
 
-	err = s.i2c.Write(true, s.address, []byte{register, patch(reg)}, 2)
+	err = s.i2c.WriteReg(s.address, register, []byte{patch(reg)})
 	if err != nil {
 		return fmt.Errorf("failed to write updated register: %w", err)
 	}
@@ -154,13 +165,7 @@ func (s Sensor) SetDataRate(rate DataRate) error {
 
 func (s Sensor) Pressure() (float32, error) {
 	// | 0x80 to enable auto-incrementing addresses while reading the 3 bytes
-	reg := byte(PressureOutXLRegister | 0x80)
-
-	if err := s.i2c.Write(false, s.address, []byte{reg}, 1); err != nil {
-		return 0, fmt.Errorf("failed to init: %w", err)
-	}
-
-	data, err := s.i2c.Read(true, s.address, 3)
+	data, err := s.i2c.ReadReg(s.address, PressureOutXLRegister|0x80, 3)
 	if err != nil {
 		return 0, fmt.Errorf("failed to read data: %w", err)
 	}
@@ -169,3 +174,118 @@ func (s Sensor) Pressure() (float32, error) {
 
 	return float32(added) / 4096.0, nil
 }
+
+// Temperature reads the onboard temperature sensor (TEMP_OUT_L/H, registers
+// 0x2B/0x2C) and returns the value in °C, using the formula from the LPS25
+// datasheet (42.5 + raw/480).
+func (s Sensor) Temperature() (float32, error) {
+	data, err := s.i2c.ReadReg(s.address, TempOutLRegister|0x80, 2)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read data: %w", err)
+	}
+
+	raw := int16(uint16(data[1])<<8 | uint16(data[0]))
+
+	return 42.5 + float32(raw)/480.0, nil
+}
+
+// Reading is a single, consistent sample of pressure and temperature, as
+// produced by ReadAll.
+type Reading struct {
+	Pressure    float32
+	Temperature float32
+}
+
+// ReadAll burst-reads PRESS_OUT_XL through TEMP_OUT_H (0x28-0x2C) in a single
+// transaction, so the pressure and temperature values come from the same
+// sample instead of two separate reads racing the data rate.
+func (s Sensor) ReadAll() (Reading, error) {
+	data, err := s.i2c.ReadReg(s.address, PressureOutXLRegister|0x80, 5)
+	if err != nil {
+		return Reading{}, fmt.Errorf("failed to read data: %w", err)
+	}
+
+	pressure := uint32(data[2])<<16 | uint32(data[1])<<8 | uint32(data[0])
+	temp := int16(uint16(data[4])<<8 | uint16(data[3]))
+
+	return Reading{
+		Pressure:    float32(pressure) / 4096.0,
+		Temperature: 42.5 + float32(temp)/480.0,
+	}, nil
+}
+
+// FIFOMode selects how the onboard FIFO collects and serves samples.
+type FIFOMode uint8
+
+const (
+	FIFOModeBypass FIFOMode = 0b000
+	FIFOModeFIFO   FIFOMode = 0b001
+	FIFOModeStream FIFOMode = 0b010
+	FIFOModeMean   FIFOMode = 0b110
+)
+
+// EnableFIFO turns on the onboard FIFO (CTRL_REG2.FIFO_EN) and configures
+// FIFO_CTRL (register 0x2E) to the given mode and watermark level. In
+// FIFOModeMean, the watermark selects the number of samples (2, 4, 8, 16 or
+// 32) the hardware averages internally for noise reduction.
+func (s *Sensor) EnableFIFO(mode FIFOMode, watermark uint8) error {
+	if err := s.patchRegisterBit(ControlRegister2, FIFOEnableBit, func(b bool) bool {
+		return true
+	}); err != nil {
+		return err
+	}
+
+	if err := s.patchRegister(FIFOCtrlRegister, func(b byte) byte {
+		b &^= FIFOModeMask
+		b |= (byte(mode) << FIFOModeShift) & FIFOModeMask
+
+		b &^= FIFOWatermarkMask
+		b |= watermark & FIFOWatermarkMask
+
+		return b
+	}); err != nil {
+		return err
+	}
+
+	s.fifoMode = mode
+
+	return nil
+}
+
+// PressureAveraged returns a noise-reduced pressure reading appropriate to
+// the FIFO mode last set with EnableFIFO.
+//
+// In FIFOModeMean, the chip already averages internally and PRESS_OUT
+// always holds that one hardware-averaged value, so a single Pressure call
+// is all there is to read. In FIFOModeFIFO/FIFOModeStream, each Pressure
+// call instead pops the oldest of several distinct buffered samples, so
+// this reads the number currently buffered (FIFO_STATUS, register 0x2F)
+// and averages them itself.
+func (s Sensor) PressureAveraged() (float32, error) {
+	if s.fifoMode == FIFOModeMean {
+		return s.Pressure()
+	}
+
+	status, err := s.readRegister(FIFOStatusRegister)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read FIFO status: %w", err)
+	}
+
+	samples := int(status & FIFOSamplesMask)
+	if samples == 0 {
+		return s.Pressure()
+	}
+
+	var sum float32
+
+	for i := 0; i < samples; i++ {
+		pressure, err := s.Pressure()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read FIFO sample %d/%d: %w", i+1, samples, err)
+		}
+
+		sum += pressure
+	}
+
+	return sum / float32(samples), nil
+}
@@ -0,0 +1,129 @@
+// Package rrd archives pressure samples into an RRDtool round-robin
+// database instead of a SQL table, so multi-day runs don't spend storage
+// and query time on a point per 40ms sample.
+package rrd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ziutek/rrd"
+
+	"go.xrstf.de/ubahnmapper/pkg/sink"
+)
+
+// Step is the base interval of the RRD file. The LPS25 running at 25 Hz
+// produces a new reading roughly every 40ms, but rrdtool's step/heartbeat
+// are whole seconds by design, so samples are archived at 1s resolution and
+// finer-grained ones (the Sink batches many per Update call, see below)
+// simply update the same primary data point multiple times before it closes.
+const Step = time.Second
+
+// heartbeat is the longest gap rrdtool tolerates between updates before it
+// marks the primary data point UNKNOWN; twice the step is the usual rule of
+// thumb.
+const heartbeat = 2 * Step
+
+// resolutions are the archive resolutions requested for the database, each
+// kept as both an AVERAGE and a MAX consolidation.
+var resolutions = []time.Duration{Step, 10 * time.Second, time.Minute}
+
+// Create creates a new round-robin archive at path with a "pressure" and an
+// "event" gauge, and AVERAGE/MAX archives at 1s, 10s and 1min resolution,
+// each covering retention worth of history.
+func Create(path string, start time.Time, retention time.Duration) error {
+	c := rrd.NewCreator(path, start, uint(Step.Seconds()))
+
+	// LPS25 pressure readings are hPa and sit around 950-1050 for real
+	// atmospheric pressure; values outside [min, max] are recorded as
+	// UNKNOWN, so the bounds need real headroom, not a placeholder range.
+	c.DS("pressure", "GAUGE", uint(heartbeat.Seconds()), 800, 1100)
+	c.DS("event", "GAUGE", uint(heartbeat.Seconds()), 0, 1e9)
+
+	for _, res := range resolutions {
+		steps := uint(res / Step)
+		rows := uint(retention / res)
+
+		c.RRA("AVERAGE", 0.5, steps, rows)
+		c.RRA("MAX", 0.5, steps, rows)
+	}
+
+	if err := c.Create(false); err != nil {
+		return fmt.Errorf("failed to create RRD database %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Sink batches points with Updater.Cache and flushes them to the RRD file
+// in a single transaction every flushEvery points, since calling Update for
+// every 40ms sample individually would dominate I/O.
+type Sink struct {
+	path    string
+	updater *rrd.Updater
+	flushN  int
+	pending int
+
+	events   map[string]float64
+	nextCode float64
+}
+
+var _ sink.Sink = &Sink{}
+
+// NewSink opens path, which must already have been created with Create,
+// and batches up to flushEvery points before writing them out.
+func NewSink(path string, flushEvery int) *Sink {
+	return &Sink{
+		path:    path,
+		updater: rrd.NewUpdater(path),
+		flushN:  flushEvery,
+		events:  map[string]float64{},
+	}
+}
+
+func (s *Sink) Write(p sink.Point) error {
+	s.updater.Cache(p.Time, p.Pressure, s.eventCode(p.Event))
+	s.pending++
+
+	if s.pending >= s.flushN {
+		return s.Flush()
+	}
+
+	return nil
+}
+
+// Flush writes all cached points to the RRD file in a single transaction.
+func (s *Sink) Flush() error {
+	if s.pending == 0 {
+		return nil
+	}
+
+	if err := s.updater.Update(); err != nil {
+		return fmt.Errorf("failed to update RRD database %s: %w", s.path, err)
+	}
+
+	s.pending = 0
+
+	return nil
+}
+
+func (s *Sink) Close() error {
+	return s.Flush()
+}
+
+// eventCode maps station event strings onto stable numeric codes, since the
+// "event" DS is a gauge and can only store floats. 0 means "no event".
+func (s *Sink) eventCode(event string) float64 {
+	if event == "" {
+		return 0
+	}
+
+	if code, ok := s.events[event]; ok {
+		return code
+	}
+
+	s.nextCode++
+	s.events[event] = s.nextCode
+
+	return s.nextCode
+}
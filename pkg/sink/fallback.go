@@ -0,0 +1,84 @@
+package sink
+
+import "sync"
+
+// Reconnecting is implemented by sinks that buffer points while degraded and
+// need to be periodically nudged to retry their primary connection; Multi
+// delegates to any wrapped sink that implements it.
+type Reconnecting interface {
+	Reconnect() error
+}
+
+// FallbackSink writes to a primary sink and, once that starts failing
+// (e.g. the train loses network connectivity), diverts points into a
+// FileSink instead. Call Reconnect periodically (e.g. on the same interval
+// as the configured flush interval) to replay the buffered points back into
+// the primary sink once it recovers.
+type FallbackSink struct {
+	primary  Sink
+	fallback *FileSink
+
+	mu       sync.Mutex
+	degraded bool
+}
+
+var (
+	_ Sink         = &FallbackSink{}
+	_ Reconnecting = &FallbackSink{}
+)
+
+// WithFallback wraps primary so that failed writes are buffered in fallback
+// instead of being lost.
+func WithFallback(primary Sink, fallback *FileSink) *FallbackSink {
+	return &FallbackSink{primary: primary, fallback: fallback}
+}
+
+func (s *FallbackSink) Write(p Point) error {
+	if !s.isDegraded() {
+		if err := s.primary.Write(p); err == nil {
+			return nil
+		}
+
+		s.setDegraded(true)
+	}
+
+	return s.fallback.Write(p)
+}
+
+// Reconnect tries to replay any points buffered while the primary sink was
+// unreachable. It's a no-op if no write has failed over yet.
+func (s *FallbackSink) Reconnect() error {
+	if !s.isDegraded() {
+		return nil
+	}
+
+	if err := s.fallback.Replay(s.primary); err != nil {
+		return err
+	}
+
+	s.setDegraded(false)
+
+	return nil
+}
+
+func (s *FallbackSink) Close() error {
+	if err := s.fallback.Close(); err != nil {
+		return err
+	}
+
+	return s.primary.Close()
+}
+
+func (s *FallbackSink) isDegraded() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.degraded
+}
+
+func (s *FallbackSink) setDegraded(degraded bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.degraded = degraded
+}
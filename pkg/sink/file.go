@@ -0,0 +1,108 @@
+package sink
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// FileSink appends points to a local line-protocol file. It's used both as
+// a standalone sink and as the durable fallback buffer for InfluxSink, so
+// points survive a network outage and can be replayed once it clears.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	w    *bufio.Writer
+}
+
+var _ Sink = &FileSink{}
+
+// NewFile opens (creating if necessary) a line-protocol file at path,
+// appending to any points already buffered there.
+func NewFile(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	return &FileSink{
+		path: path,
+		f:    f,
+		w:    bufio.NewWriter(f),
+	}, nil
+}
+
+func (s *FileSink) Write(p Point) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.WriteString(encodeLine(p)); err != nil {
+		return fmt.Errorf("failed to buffer point to %s: %w", s.path, err)
+	}
+
+	return s.w.Flush()
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+
+	return s.f.Close()
+}
+
+// Replay reads back every point buffered so far and writes it to dest. Once
+// every point has been replayed successfully, the file is truncated so
+// nothing is shipped twice; if dest fails partway through, the file is left
+// untouched and the next Replay call starts over from the beginning.
+func (s *FileSink) Replay(dest Sink) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := s.f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %w", s.path, err)
+	}
+
+	scanner := bufio.NewScanner(s.f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		p, err := decodeLine(line)
+		if err != nil {
+			return fmt.Errorf("failed to parse buffered point: %w", err)
+		}
+
+		if err := dest.Write(p); err != nil {
+			return fmt.Errorf("failed to replay buffered point: %w", err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read buffered points: %w", err)
+	}
+
+	if err := s.f.Truncate(0); err != nil {
+		return fmt.Errorf("failed to clear %s: %w", s.path, err)
+	}
+
+	if _, err := s.f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %w", s.path, err)
+	}
+
+	s.w = bufio.NewWriter(s.f)
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+)
+
+// InfluxSink streams points into an InfluxDB v2 bucket through its
+// blocking write API, so a failed write returns a real error instead of
+// being swallowed by the async API's background error channel. That error
+// is what lets a wrapping FallbackSink notice the server is unreachable and
+// divert to its line-protocol file buffer.
+type InfluxSink struct {
+	client   influxdb2.Client
+	writeAPI api.WriteAPIBlocking
+}
+
+var _ Sink = &InfluxSink{}
+
+// NewInflux connects to an InfluxDB v2 server and prepares a blocking
+// writer for org/bucket. flushInterval is unused by the blocking API but
+// kept so callers don't need to change when switching write strategies.
+func NewInflux(url, token, org, bucket string, flushInterval time.Duration) *InfluxSink {
+	client := influxdb2.NewClient(url, token)
+
+	return &InfluxSink{
+		client:   client,
+		writeAPI: client.WriteAPIBlocking(org, bucket),
+	}
+}
+
+func (s *InfluxSink) Write(p Point) error {
+	tags := make(map[string]string, len(p.Tags)+1)
+	for k, v := range p.Tags {
+		tags[k] = v
+	}
+
+	if p.Event != "" {
+		tags["event"] = p.Event
+	}
+
+	fields := map[string]interface{}{
+		"pressure": p.Pressure,
+		"raw":      p.Raw,
+	}
+
+	point := write.NewPoint("ubahnmapper", tags, fields, p.Time)
+
+	if err := s.writeAPI.WritePoint(context.Background(), point); err != nil {
+		return fmt.Errorf("failed to write point to influx: %w", err)
+	}
+
+	return nil
+}
+
+func (s *InfluxSink) Close() error {
+	s.client.Close()
+
+	return nil
+}
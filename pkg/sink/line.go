@@ -0,0 +1,142 @@
+package sink
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// encodeLine renders p as a single InfluxDB line-protocol line, e.g.
+//
+//	ubahnmapper,run_id=2026-07-26,event=Alexanderplatz\ an pressure=1013.45,raw=1013.45 1690000000000000000
+func encodeLine(p Point) string {
+	var sb strings.Builder
+
+	sb.WriteString("ubahnmapper")
+
+	keys := make([]string, 0, len(p.Tags))
+	for k := range p.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(&sb, ",%s=%s", escapeTag(k), escapeTag(p.Tags[k]))
+	}
+
+	if p.Event != "" {
+		fmt.Fprintf(&sb, ",event=%s", escapeTag(p.Event))
+	}
+
+	fmt.Fprintf(&sb, " pressure=%v,raw=%v %d\n", p.Pressure, p.Raw, p.Time.UnixNano())
+
+	return sb.String()
+}
+
+// decodeLine parses a line written by encodeLine back into a Point. It only
+// has to round-trip what this package itself produces, so it doesn't aim
+// for full line-protocol compliance.
+func decodeLine(line string) (Point, error) {
+	measurementAndTags, fieldsAndTime, ok := cut(line, ' ')
+	if !ok {
+		return Point{}, fmt.Errorf("missing field set in line %q", line)
+	}
+
+	fieldSet, timestamp, ok := cut(fieldsAndTime, ' ')
+	if !ok {
+		return Point{}, fmt.Errorf("missing timestamp in line %q", line)
+	}
+
+	p := Point{Tags: map[string]string{}}
+
+	for i, part := range split(measurementAndTags, ',') {
+		if i == 0 {
+			continue // measurement name, always "ubahnmapper"
+		}
+
+		key, value, ok := cut(part, '=')
+		if !ok {
+			return Point{}, fmt.Errorf("invalid tag %q in line %q", part, line)
+		}
+
+		value = unescapeTag(value)
+
+		if key == "event" {
+			p.Event = value
+		} else {
+			p.Tags[unescapeTag(key)] = value
+		}
+	}
+
+	for _, part := range strings.Split(fieldSet, ",") {
+		key, value, ok := cut(part, '=')
+		if !ok {
+			return Point{}, fmt.Errorf("invalid field %q in line %q", part, line)
+		}
+
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return Point{}, fmt.Errorf("invalid value for field %q: %w", key, err)
+		}
+
+		switch key {
+		case "pressure":
+			p.Pressure = f
+		case "raw":
+			p.Raw = f
+		}
+	}
+
+	ns, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid timestamp %q: %w", timestamp, err)
+	}
+
+	p.Time = time.Unix(0, ns)
+
+	return p, nil
+}
+
+// cut splits s at the first sep that isn't escaped with a backslash.
+func cut(s string, sep byte) (before, after string, found bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++ // skip the escaped character
+		case sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+
+	return s, "", false
+}
+
+// split splits s at every sep that isn't escaped with a backslash.
+func split(s string, sep byte) []string {
+	var parts []string
+
+	for {
+		before, after, found := cut(s, sep)
+		parts = append(parts, before)
+
+		if !found {
+			return parts
+		}
+
+		s = after
+	}
+}
+
+var tagEscaper = strings.NewReplacer(" ", `\ `, ",", `\,`, "=", `\=`)
+
+func escapeTag(s string) string {
+	return tagEscaper.Replace(s)
+}
+
+var tagUnescaper = strings.NewReplacer(`\ `, " ", `\,`, ",", `\=`, "=")
+
+func unescapeTag(s string) string {
+	return tagUnescaper.Replace(s)
+}
@@ -0,0 +1,65 @@
+package sink
+
+import "errors"
+
+// Multi fans a single stream of points out to several sinks at once, e.g.
+// InfluxDB for live viewing and an RRD archive for long-term history.
+type Multi struct {
+	sinks []Sink
+}
+
+var (
+	_ Sink         = &Multi{}
+	_ Reconnecting = &Multi{}
+)
+
+// NewMulti combines sinks into one Sink that writes every point to all of
+// them.
+func NewMulti(sinks ...Sink) *Multi {
+	return &Multi{sinks: sinks}
+}
+
+// Write attempts every sink even if an earlier one fails, so a transient
+// error in one (e.g. the Influx/CSV fallback stack) doesn't also cost the
+// point in an unrelated sink like the RRD archive. All errors are returned
+// together.
+func (m *Multi) Write(p Point) error {
+	var errs []error
+
+	for _, s := range m.sinks {
+		if err := s.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Reconnect delegates to every wrapped sink that implements Reconnecting
+// (e.g. a FallbackSink buried inside Multi), so Recorder's periodic
+// reconnect attempt still reaches it.
+func (m *Multi) Reconnect() error {
+	var errs []error
+
+	for _, s := range m.sinks {
+		if r, ok := s.(Reconnecting); ok {
+			if err := r.Reconnect(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *Multi) Close() error {
+	var errs []error
+
+	for _, s := range m.sinks {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
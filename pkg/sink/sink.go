@@ -0,0 +1,26 @@
+// Package sink ships pressure samples recorded during a ride to a
+// time-series backend, using the InfluxDB line protocol as the common wire
+// format.
+package sink
+
+import "time"
+
+// Point is a single line-protocol point: the "ubahnmapper" measurement,
+// tagged by run and (optionally) by the current station event, carrying
+// the pressure reading in hPa in both Pressure and Raw. The Recorder writes
+// the same absolute reading into both fields; no normalization is applied.
+type Point struct {
+	Time     time.Time
+	Pressure float64
+	Raw      float64
+	Event    string
+	Tags     map[string]string
+}
+
+// Sink accepts points as they're recorded. Implementations must be safe for
+// use from a single writer goroutine; callers should not assume they are
+// safe for concurrent Write calls unless documented otherwise.
+type Sink interface {
+	Write(p Point) error
+	Close() error
+}